@@ -0,0 +1,415 @@
+// Command mdp renders a Markdown file (or directory of them) to HTML,
+// optionally previewing it in a browser or serving it with live reload.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/zrks/mdp/pkg/preview"
+	"github.com/zrks/mdp/pkg/render"
+	"github.com/zrks/mdp/pkg/sanitize"
+)
+
+func main() {
+	filename := flag.String("file", "", "Markdown file to preview")
+	skipPreview := flag.Bool("s", false, "Skip auto-preview")
+	templateFile := flag.String("t", "", "Alternative HTML template file")
+	templateDir := flag.String("templates", "", "Directory of layout templates selected via front-matter `layout:`")
+	serveMode := flag.Bool("serve", false, "Start a live-reload HTTP preview server instead of writing index.html")
+	addr := flag.String("addr", ":8080", "Address to listen on when -serve is set")
+	outDir := flag.String("out", "", "Output directory when -file is a directory (default: <file>/_site)")
+	sanitizePolicy := flag.String("sanitize", "ugc", "HTML sanitization policy: strict, ugc, or none")
+	flag.Parse()
+
+	if *filename == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	policy := sanitize.ByName(*sanitizePolicy)
+
+	info, err := os.Stat(*filename)
+	if err != nil {
+		log.Fatalf("stat %q: %v", *filename, err)
+	}
+
+	if info.IsDir() {
+		out := *outDir
+		if out == "" {
+			out = filepath.Join(*filename, "_site")
+		}
+		if err := runDir(*filename, out, *templateFile, *templateDir, policy); err != nil {
+			log.Fatalf("static site generation failed: %v", err)
+		}
+		return
+	}
+
+	if *serveMode {
+		if err := serve(*filename, *templateFile, *templateDir, *addr, policy); err != nil {
+			log.Fatalf("serve failed: %v", err)
+		}
+		return
+	}
+
+	if err := run(*filename, os.Stdout, *skipPreview, *templateFile, *templateDir, policy); err != nil {
+		log.Fatalf("preview failed: %v", err)
+	}
+}
+
+// run reads the Markdown file at filename, renders it using templateFile
+// (or a front-matter-selected layout from templateDir), writes the result
+// to index.html in the same directory, prints the output path to w, and
+// optionally invokes a preview command.
+func run(filename string, w io.Writer, skipPreview bool, templateFile, templateDir string, policy sanitize.Policy) error {
+	// Read source Markdown
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("reading markdown %q: %w", filename, err)
+	}
+
+	// Render HTML from template
+	html, err := render.Parse(data, render.Options{
+		TemplateFile: templateFile,
+		TemplateDir:  templateDir,
+		Policy:       &policy,
+	})
+	if err != nil {
+		return fmt.Errorf("rendering content from template %q: %w", templateFile, err)
+	}
+
+	// Determine output path: same dir as source, named index.html
+	outDir := filepath.Dir(filename)
+	outPath := filepath.Join(outDir, "index.html")
+
+	// Inform caller where file will be written
+	fmt.Fprintln(w, outPath)
+
+	// Write out HTML file
+	if err := saveHTML(outPath, html); err != nil {
+		return fmt.Errorf("writing HTML to %q: %w", outPath, err)
+	}
+
+	// Skip preview if requested
+	if skipPreview {
+		return nil
+	}
+
+	// Preview the generated file
+	if err := preview.Open(outPath); err != nil {
+		return fmt.Errorf("preview failed for %q: %w", outPath, err)
+	}
+
+	return nil
+}
+
+func saveHTML(filename string, data []byte) error {
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("writing HTML file %q: %w", filename, err)
+	}
+	return nil
+}
+
+// metaString returns meta[key] as a string, or fallback if the key is
+// absent or not a string.
+func metaString(meta map[string]interface{}, key, fallback string) string {
+	if v, ok := meta[key].(string); ok {
+		return v
+	}
+	return fallback
+}
+
+// dirPage describes one rendered page for the purposes of a generated
+// directory index listing.
+type dirPage struct {
+	Title string
+	Href  string
+	Date  string
+}
+
+// dirIndexTemplate renders the auto-generated per-directory listing used by
+// runDir when a directory has no index.md of its own.
+const dirIndexTemplate = `<!DOCTYPE html><html><head><meta http-equiv="content-type" content="text/html; charset=utf-8"><title>Index</title></head><body><ul>{{ range . }}<li><a href="{{ .Href }}">{{ .Title }}</a>{{ if .Date }} &mdash; {{ .Date }}{{ end }}</li>{{ end }}</ul></body></html>`
+
+// mdLinkRegex matches href attributes pointing at a local .md file so
+// runDir can rewrite cross-document links to the generated .html pages.
+var mdLinkRegex = regexp.MustCompile(`href="([^":]+)\.md(#[^"]*)?"`)
+
+// rewriteMarkdownLinks rewrites href="foo.md" (and "foo.md#frag") to
+// href="foo.html" so links between rendered pages keep working.
+func rewriteMarkdownLinks(html []byte) []byte {
+	return mdLinkRegex.ReplaceAll(html, []byte(`href="$1.html$2"`))
+}
+
+// runDir walks the directory tree rooted at srcDir, rendering every *.md
+// file into a mirrored tree under outDir, copying non-Markdown assets
+// through unchanged, and writing a per-directory index.html listing for
+// directories that don't already render their own (e.g. via index.md).
+func runDir(srcDir, outDir, templateFile, templateDir string, policy sanitize.Policy) error {
+	pagesByDir := make(map[string][]dirPage)
+
+	err := filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return fmt.Errorf("computing relative path for %q: %w", path, err)
+		}
+		destDir := filepath.Join(outDir, filepath.Dir(rel))
+		if err := os.MkdirAll(destDir, 0755); err != nil {
+			return fmt.Errorf("creating output directory %q: %w", destDir, err)
+		}
+
+		if filepath.Ext(path) != ".md" {
+			return copyAsset(path, filepath.Join(destDir, filepath.Base(path)))
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading markdown %q: %w", path, err)
+		}
+
+		rendered, err := render.Parse(data, render.Options{
+			TemplateFile: templateFile,
+			TemplateDir:  templateDir,
+			Policy:       &policy,
+		})
+		if err != nil {
+			return fmt.Errorf("rendering %q: %w", path, err)
+		}
+		rendered = rewriteMarkdownLinks(rendered)
+
+		destName := strings.TrimSuffix(filepath.Base(path), ".md") + ".html"
+		destPath := filepath.Join(destDir, destName)
+		if err := saveHTML(destPath, rendered); err != nil {
+			return err
+		}
+
+		meta, err := render.FrontMatter(data)
+		if err != nil {
+			return err
+		}
+
+		dirRel := filepath.Dir(rel)
+		pagesByDir[dirRel] = append(pagesByDir[dirRel], dirPage{
+			Title: metaString(meta, "title", strings.TrimSuffix(filepath.Base(path), ".md")),
+			Href:  destName,
+			Date:  metaString(meta, "date", ""),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("walking %q: %w", srcDir, err)
+	}
+
+	for dir, pages := range pagesByDir {
+		if err := writeDirIndex(filepath.Join(outDir, dir), pages); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyAsset copies a non-Markdown file through to the output tree
+// unchanged.
+func copyAsset(src, dest string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("reading asset %q: %w", src, err)
+	}
+	if err := os.WriteFile(dest, data, 0644); err != nil {
+		return fmt.Errorf("writing asset %q: %w", dest, err)
+	}
+	return nil
+}
+
+// writeDirIndex writes an index.html listing pages, unless the directory
+// already has one (typically rendered from an index.md).
+func writeDirIndex(dir string, pages []dirPage) error {
+	indexPath := filepath.Join(dir, "index.html")
+	if _, err := os.Stat(indexPath); err == nil {
+		return nil
+	}
+
+	tmpl, err := template.New("mdp-dir-index").Parse(dirIndexTemplate)
+	if err != nil {
+		return fmt.Errorf("parsing directory index template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, pages); err != nil {
+		return fmt.Errorf("executing directory index template: %w", err)
+	}
+
+	return saveHTML(indexPath, buf.Bytes())
+}
+
+// reloadScript is appended to every server-rendered page so the browser
+// reloads itself when the source file or its template changes.
+const reloadScript = `<script>new EventSource("/mdp-reload").onmessage = function() { location.reload(); };</script>`
+
+// injectReload appends reloadScript just before the closing </body> tag (or
+// at the end of the document if none is found).
+func injectReload(html []byte) []byte {
+	idx := bytes.LastIndex(html, []byte("</body>"))
+	if idx == -1 {
+		return append(html, []byte(reloadScript)...)
+	}
+	out := make([]byte, 0, len(html)+len(reloadScript))
+	out = append(out, html[:idx]...)
+	out = append(out, []byte(reloadScript)...)
+	out = append(out, html[idx:]...)
+	return out
+}
+
+// serve starts an HTTP preview server that re-renders filename on every
+// request and pushes a reload event to connected browsers whenever the
+// source file or its template changes. It replaces the fragile
+// time.Sleep(2 * time.Second) used by preview.Open with a watch loop that
+// works uniformly across operating systems.
+func serve(filename, templateFile, templateDir, addr string, policy sanitize.Policy) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("starting file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	watchTargets := []string{filename}
+	if templateFile != "" {
+		watchTargets = append(watchTargets, templateFile)
+	}
+	if templateDir != "" {
+		watchTargets = append(watchTargets, templateDir)
+	}
+	for _, target := range watchTargets {
+		if err := watcher.Add(target); err != nil {
+			return fmt.Errorf("watching %q: %w", target, err)
+		}
+	}
+
+	hub := newReloadHub()
+	go hub.watch(watcher)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mdp-reload", hub.ServeHTTP)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		rendered, err := render.Parse(data, render.Options{
+			TemplateFile: templateFile,
+			TemplateDir:  templateDir,
+			Policy:       &policy,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(injectReload(rendered))
+	})
+
+	log.Printf("serving %q on http://%s", filename, addr)
+	return http.ListenAndServe(addr, mux)
+}
+
+// reloadHub fans out file-change notifications as Server-Sent Events so
+// connected browsers can reload themselves.
+type reloadHub struct {
+	mu      sync.Mutex
+	clients map[chan struct{}]bool
+}
+
+func newReloadHub() *reloadHub {
+	return &reloadHub{clients: make(map[chan struct{}]bool)}
+}
+
+// watch relays fsnotify events to connected clients until the watcher is
+// closed.
+func (h *reloadHub) watch(watcher *fsnotify.Watcher) {
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				h.broadcast()
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("watch error: %v", err)
+		}
+	}
+}
+
+func (h *reloadHub) broadcast() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// ServeHTTP streams reload notifications to a single browser tab over
+// Server-Sent Events until the request context is canceled.
+func (h *reloadHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch := make(chan struct{}, 1)
+	h.mu.Lock()
+	h.clients[ch] = true
+	h.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		delete(h.clients, ch)
+		h.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-ch:
+			fmt.Fprint(w, "data: reload\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
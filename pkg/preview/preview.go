@@ -0,0 +1,51 @@
+// Package preview launches the host OS's default viewer/browser on a
+// rendered file.
+package preview
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// Open opens the given file in the user's default viewer/browser, based on
+// the host operating system. It locates the appropriate open command, runs
+// it, and waits briefly to ensure the viewer has time to launch.
+func Open(filePath string) error {
+	// Select the appropriate command and initial arguments per OS
+	var cmdName string
+	var cmdArgs []string
+
+	switch runtime.GOOS {
+	case "linux":
+		cmdName = "xdg-open"
+	case "windows":
+		cmdName = "cmd.exe"
+		cmdArgs = []string{"/C", "start"}
+	case "darwin":
+		cmdName = "open"
+	default:
+		return fmt.Errorf("unsupported OS: %q", runtime.GOOS)
+	}
+
+	// Append the file to open
+	cmdArgs = append(cmdArgs, filePath)
+
+	// Resolve the full path to the executable
+	exePath, err := exec.LookPath(cmdName)
+	if err != nil {
+		return fmt.Errorf("executable %q not found: %w", cmdName, err)
+	}
+
+	// Execute the command
+	cmd := exec.Command(exePath, cmdArgs...)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running %q with args %v: %w", cmdName, cmdArgs, err)
+	}
+
+	// Allow viewer time to start
+	time.Sleep(2 * time.Second)
+
+	return nil
+}
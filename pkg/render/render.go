@@ -0,0 +1,266 @@
+// Package render turns Markdown (with optional YAML/TOML front matter)
+// into sanitized, templated HTML. It is the library half of mdp: the CLI
+// in cmd/mdp is a thin wrapper around Parse.
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"path/filepath"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/BurntSushi/toml"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/zrks/mdp/pkg/sanitize"
+)
+
+// chromaStyleName selects the syntax-highlighting color scheme applied to
+// fenced code blocks.
+const chromaStyleName = "github"
+
+// chromaCSS is the stylesheet for chromaStyleName, generated once at
+// startup and injected into every rendered page alongside the class-based
+// highlighting markup goldmark produces.
+var chromaCSS = mustChromaCSS(chromaStyleName)
+
+func mustChromaCSS(styleName string) string {
+	style := styles.Get(styleName)
+	if style == nil {
+		style = styles.Fallback
+	}
+
+	var buf bytes.Buffer
+	if err := chromahtml.New(chromahtml.WithClasses(true)).WriteCSS(&buf, style); err != nil {
+		panic(fmt.Sprintf("generating chroma stylesheet for %q: %v", styleName, err))
+	}
+	return buf.String()
+}
+
+// defaultTemplate is used whenever Options.TemplateFile is empty and either
+// the document has no front-matter `layout` or Options.TemplateDir is empty.
+// A `layout` naming a file missing from Options.TemplateDir is an error.
+const defaultTemplate = `<!DOCTYPE html><html><head><meta http-equiv="content-type" content="text/html; charset=utf-8"> <title>{{ .Title }}</title> </head> <body> {{ .Body }} </body> </html>`
+
+// Content is the data made available to an HTML template while rendering
+// a Markdown document.
+type Content struct {
+	Title string
+	Body  template.HTML
+	Meta  map[string]interface{}
+}
+
+// Renderer converts Markdown source into HTML. It lets callers swap the
+// Markdown engine (or wrap it with extra extensions) without touching
+// Parse.
+type Renderer interface {
+	Render(markdown []byte) ([]byte, error)
+}
+
+// goldmarkRenderer adapts a configured goldmark.Markdown to the Renderer
+// interface.
+type goldmarkRenderer struct {
+	md goldmark.Markdown
+}
+
+// NewGoldmarkRenderer builds mdp's default Renderer: CommonMark plus the
+// GitHub-flavoured extensions (tables, strikethrough, task lists,
+// autolinks) and syntax highlighting for fenced code blocks that carry a
+// language tag.
+func NewGoldmarkRenderer() Renderer {
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			highlighting.NewHighlighting(
+				highlighting.WithStyle(chromaStyleName),
+				// Emit CSS classes rather than inline style="..." attributes:
+				// the sanitize.Policy below strips style attributes, which
+				// would otherwise silently discard all syntax highlighting.
+				highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+			),
+		),
+		goldmark.WithRendererOptions(
+			// Raw HTML is stripped by the sanitize.Policy applied downstream,
+			// so we let goldmark pass it through rather than escaping it here.
+			html.WithUnsafe(),
+		),
+	)
+	return &goldmarkRenderer{md: md}
+}
+
+func (g *goldmarkRenderer) Render(markdown []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := g.md.Convert(markdown, &buf); err != nil {
+		return nil, fmt.Errorf("converting markdown: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Options configures Parse. The zero value renders with the default
+// goldmark Renderer, the built-in template, and sanitize.UGCPolicy.
+type Options struct {
+	// Renderer overrides the default CommonMark/GFM renderer. Nil selects
+	// NewGoldmarkRenderer().
+	Renderer Renderer
+
+	// TemplateFile, if set, is parsed as the HTML template and takes
+	// priority over a front-matter `layout`.
+	TemplateFile string
+
+	// TemplateDir is searched for a layout named by a document's
+	// front-matter `layout` key when TemplateFile is empty.
+	TemplateDir string
+
+	// Policy overrides the default sanitize.UGCPolicy(). Nil selects the
+	// default; to render with no sanitization at all, pass an explicit
+	// pointer to sanitize.NonePolicy().
+	Policy *sanitize.Policy
+}
+
+// Parse converts the given Markdown input into sanitized HTML by applying
+// either the built-in default template, a front-matter-selected layout, or
+// an explicit template file. It returns the rendered HTML bytes or an
+// error.
+func Parse(markdown []byte, opts Options) ([]byte, error) {
+	renderer := opts.Renderer
+	if renderer == nil {
+		renderer = NewGoldmarkRenderer()
+	}
+	policy := sanitize.UGCPolicy()
+	if opts.Policy != nil {
+		policy = *opts.Policy
+	}
+
+	// 1. Split off front matter, if any, before rendering the body
+	meta, body, err := splitFrontMatter(markdown)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Convert Markdown → HTML
+	rendered, err := renderer.Render(body)
+	if err != nil {
+		return nil, fmt.Errorf("rendering markdown: %w", err)
+	}
+
+	// 3. Sanitize HTML for safe output
+	sanitized := policy.Sanitize(rendered)
+
+	// 4. Resolve which template to use: an explicit TemplateFile wins, then
+	// a front-matter `layout` resolved against TemplateDir, then the
+	// built-in default.
+	tmpl, err := template.New("mdp").Parse(defaultTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing default template: %w", err)
+	}
+
+	switch layout := metaString(meta, "layout", ""); {
+	case opts.TemplateFile != "":
+		tmpl, err = template.ParseFiles(opts.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("parsing template file %q: %w", opts.TemplateFile, err)
+		}
+	case layout != "" && opts.TemplateDir != "":
+		layoutPath := filepath.Join(opts.TemplateDir, layout+".html")
+		tmpl, err = template.ParseFiles(layoutPath)
+		if err != nil {
+			return nil, fmt.Errorf("parsing layout template %q: %w", layoutPath, err)
+		}
+	}
+
+	// 5. Prepare data for the template. The chroma stylesheet is trusted,
+	// self-generated content, so it's appended after sanitization rather
+	// than passed through the policy (which would need to allow <style>
+	// elements, reopening a CSS-injection vector for raw HTML from the
+	// document itself).
+	data := Content{
+		Title: metaString(meta, "title", "zrks"),
+		Body:  template.HTML("<style>" + chromaCSS + "</style>" + string(sanitized)),
+		Meta:  meta,
+	}
+
+	// 6. Execute the template into a buffer
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("executing template: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// FrontMatter parses and returns only a document's front-matter metadata
+// (nil if none is present), without rendering its body. Callers that build
+// page listings (titles, dates) can use this instead of paying for a full
+// render.
+func FrontMatter(markdown []byte) (map[string]interface{}, error) {
+	meta, _, err := splitFrontMatter(markdown)
+	return meta, err
+}
+
+var (
+	yamlFrontMatter = []byte("---")
+	tomlFrontMatter = []byte("+++")
+)
+
+// splitFrontMatter extracts an optional YAML (`---`) or TOML (`+++`)
+// front-matter block from the start of a Markdown document. It returns the
+// parsed metadata (nil if none was present) and the remaining document body.
+func splitFrontMatter(markdown []byte) (map[string]interface{}, []byte, error) {
+	markdown = bytes.TrimPrefix(markdown, []byte("\ufeff"))
+
+	delim, unmarshal := detectFrontMatter(markdown)
+	if delim == nil {
+		return nil, markdown, nil
+	}
+
+	rest := markdown[len(delim):]
+	rest = bytes.TrimPrefix(rest, []byte("\n"))
+	end := bytes.Index(rest, append([]byte("\n"), delim...))
+	if end == -1 {
+		// No closing delimiter: treat the whole file as a body rather than
+		// silently dropping content.
+		return nil, markdown, nil
+	}
+
+	raw := rest[:end]
+	body := rest[end+1+len(delim):]
+	body = bytes.TrimPrefix(body, []byte("\n"))
+
+	meta := map[string]interface{}{}
+	if err := unmarshal(raw, &meta); err != nil {
+		// The opening delimiter may just be part of the document (a
+		// thematic break, a line of dashes) rather than real front matter.
+		// Treat it as body text rather than failing the whole render.
+		return nil, markdown, nil
+	}
+
+	return meta, body, nil
+}
+
+// detectFrontMatter reports which front-matter delimiter (if any) opens the
+// document, along with the matching unmarshal function.
+func detectFrontMatter(markdown []byte) ([]byte, func([]byte, interface{}) error) {
+	switch {
+	case bytes.HasPrefix(markdown, yamlFrontMatter):
+		return yamlFrontMatter, yaml.Unmarshal
+	case bytes.HasPrefix(markdown, tomlFrontMatter):
+		return tomlFrontMatter, toml.Unmarshal
+	default:
+		return nil, nil
+	}
+}
+
+// metaString returns meta[key] as a string, or fallback if the key is
+// absent or not a string.
+func metaString(meta map[string]interface{}, key, fallback string) string {
+	if v, ok := meta[key].(string); ok {
+		return v
+	}
+	return fallback
+}
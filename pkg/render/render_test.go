@@ -0,0 +1,29 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestHighlightedCodeSurvivesSanitization guards against the highlighter
+// and sanitizer disagreeing: if the highlighter ever goes back to emitting
+// inline style="..." attributes, the default Policy strips them and every
+// highlighted code block silently renders unstyled.
+func TestHighlightedCodeSurvivesSanitization(t *testing.T) {
+	markdown := []byte("```go\npackage main\n```\n")
+
+	out, err := Parse(markdown, Options{})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if !bytes.Contains(out, []byte("<style>")) {
+		t.Fatalf("expected the chroma stylesheet to be injected, got: %s", out)
+	}
+	if !bytes.Contains(out, []byte(`class="`)) {
+		t.Fatalf("expected chroma's class-based highlighting markup to survive sanitization, got: %s", out)
+	}
+	if bytes.Contains(out, []byte(`style="color`)) {
+		t.Fatalf("found inline color styling in sanitized output; highlighter should emit classes, not inline styles: %s", out)
+	}
+}
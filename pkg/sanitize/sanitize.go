@@ -0,0 +1,57 @@
+// Package sanitize provides allow-list HTML sanitization policies for
+// mdp-rendered output, built on bluemonday.
+package sanitize
+
+import "github.com/microcosm-cc/bluemonday"
+
+// Policy controls which HTML elements and attributes survive sanitization.
+// It wraps bluemonday's allow-list sanitizer rather than pattern-matching
+// against known-bad tags, so it can't be bypassed by a markup shape its
+// author didn't anticipate.
+type Policy struct {
+	p *bluemonday.Policy
+}
+
+// Sanitize returns html with everything not permitted by the policy
+// stripped out.
+func (p Policy) Sanitize(html []byte) []byte {
+	if p.p == nil {
+		return html
+	}
+	return p.p.SanitizeBytes(html)
+}
+
+// StrictPolicy strips all HTML tags, leaving plain text only.
+func StrictPolicy() Policy { return Policy{p: bluemonday.StrictPolicy()} }
+
+// UGCPolicy allows the common "user generated content" elements and
+// attributes that CommonMark/GFM rendering produces — headers, lists,
+// tables, links, images, code blocks — while stripping scripts, styles,
+// and event handlers. This is mdp's default.
+//
+// It additionally allows a "class" attribute on pre/code/span: goldmark's
+// syntax-highlighting extension marks up fenced code blocks with CSS
+// classes (not inline styles), and bluemonday's preset doesn't allow them
+// by default, which would otherwise strip all highlighting.
+func UGCPolicy() Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").OnElements("pre", "code", "span")
+	return Policy{p: p}
+}
+
+// NonePolicy performs no sanitization at all. Only use it for input you
+// already trust.
+func NonePolicy() Policy { return Policy{} }
+
+// ByName resolves a policy name (as taken from the -sanitize flag) to a
+// Policy, defaulting to UGCPolicy for unrecognized or empty input.
+func ByName(name string) Policy {
+	switch name {
+	case "strict":
+		return StrictPolicy()
+	case "none":
+		return NonePolicy()
+	default:
+		return UGCPolicy()
+	}
+}
@@ -0,0 +1,76 @@
+package sanitize
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// xssVectors are HTML snippets that a naive deny-list sanitizer is known to
+// miss: nested/obfuscated tags, mismatched attribute quoting, unusual
+// casing, and non-script vectors like data: URLs and SVG event handlers.
+var xssVectors = []string{
+	`<script>alert(1)</script>`,
+	`<SCRIPT >alert(1)</SCRIPT >`,
+	`<scr<script>ipt>alert(1)</scr</script>ipt>`,
+	`<img src=x onerror=alert(1)>`,
+	`<img src="x" onerror="alert(1)" >`,
+	`<a href="javascript:alert(1)">click</a>`,
+	`<a href="data:text/html,<script>alert(1)</script>">click</a>`,
+	`<svg onload=alert(1)>`,
+	`<iframe src="javascript:alert(1)"></iframe>`,
+	`<body onload=alert(1)>`,
+	`<div style="background:url(javascript:alert(1))">x</div>`,
+	`<object data="javascript:alert(1)"></object>`,
+	`<base href="javascript:alert(1)//">`,
+	`<meta http-equiv="refresh" content="0;url=javascript:alert(1)">`,
+}
+
+func TestUGCPolicySanitizesXSSVectors(t *testing.T) {
+	policy := UGCPolicy()
+	for _, vector := range xssVectors {
+		out := policy.Sanitize([]byte(vector))
+		if bytes.Contains(bytes.ToLower(out), []byte("javascript:")) {
+			t.Errorf("UGCPolicy left a javascript: URL in output for %q: %q", vector, out)
+		}
+		if strings.Contains(strings.ToLower(string(out)), "onerror=") ||
+			strings.Contains(strings.ToLower(string(out)), "onload=") {
+			t.Errorf("UGCPolicy left an event handler in output for %q: %q", vector, out)
+		}
+		if bytes.Contains(bytes.ToLower(out), []byte("<script")) {
+			t.Errorf("UGCPolicy left a <script> tag in output for %q: %q", vector, out)
+		}
+	}
+}
+
+func TestStrictPolicyStripsAllTags(t *testing.T) {
+	policy := StrictPolicy()
+	out := policy.Sanitize([]byte(`<p>hello <b>world</b></p><script>alert(1)</script>`))
+	if bytes.ContainsAny(out, "<>") {
+		t.Errorf("StrictPolicy left markup in output: %q", out)
+	}
+}
+
+func TestNonePolicyPassesThrough(t *testing.T) {
+	policy := NonePolicy()
+	in := `<script>alert(1)</script>`
+	if out := string(policy.Sanitize([]byte(in))); out != in {
+		t.Errorf("NonePolicy modified input: got %q, want %q", out, in)
+	}
+}
+
+func TestByName(t *testing.T) {
+	cases := map[string]Policy{
+		"strict": StrictPolicy(),
+		"ugc":    UGCPolicy(),
+		"none":   NonePolicy(),
+		"":       UGCPolicy(),
+		"bogus":  UGCPolicy(),
+	}
+	for name, want := range cases {
+		got := ByName(name)
+		if (got.p == nil) != (want.p == nil) {
+			t.Errorf("ByName(%q) = %#v, want policy matching %#v", name, got, want)
+		}
+	}
+}